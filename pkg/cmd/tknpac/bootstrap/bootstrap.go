@@ -23,7 +23,7 @@ const (
 	defaultProviderType    = "github-app"
 )
 
-var providerTargets = []string{"github-app", "github-enterprise-app"}
+var providerTargets = []string{"github-app", "github-enterprise-app", "gitea"}
 
 type bootstrapOpts struct {
 	providerType    string
@@ -42,6 +42,8 @@ type bootstrapOpts struct {
 	GithubApplicationURL   string
 	GithubOrganizationName string
 	forceGitHubApp         bool
+
+	GiteaAPIURL string
 }
 
 const indexTmpl = `
@@ -158,6 +160,7 @@ func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
 		},
 	}
 	cmd.AddCommand(GithubApp(run, ioStreams))
+	cmd.AddCommand(GiteaApp(run, ioStreams))
 
 	addCommonFlags(cmd, ioStreams)
 	addGithubAppFlag(cmd, opts)
@@ -0,0 +1,160 @@
+package bootstrap
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// giteaOAuthCredentials are the Client ID/Secret the user copies over from
+// the Gitea "Create OAuth2 Application" page.
+type giteaOAuthCredentials struct {
+	ClientID     string
+	ClientSecret string
+}
+
+// GiteaApp is the `tkn pac bootstrap gitea` command: it walks the user
+// through creating a Gitea OAuth2 application for Pipelines as Code,
+// symmetric to GithubApp for GitHub Apps.
+func GiteaApp(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &bootstrapOpts{
+		ioStreams:    ioStreams,
+		providerType: "gitea",
+	}
+
+	cmd := &cobra.Command{
+		Use:   "gitea",
+		Long:  "A command helper to help you create a Gitea OAuth2 Application for Pipelines as Code",
+		Short: "Create PAC Gitea OAuth2 Application",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			opts.cliOpts = cli.NewCliOptions(cmd)
+			opts.ioStreams.SetColorEnabled(!opts.cliOpts.NoColoring)
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+
+			var err error
+			opts.targetNamespace, err = DetectPacInstallation(ctx, opts.targetNamespace, run)
+			if err != nil {
+				return err
+			}
+
+			if opts.GiteaAPIURL == "" {
+				opts.GiteaAPIURL, err = askGiteaBaseURL(opts.ioStreams)
+				if err != nil {
+					return err
+				}
+			}
+
+			return createGiteaSecret(ctx, run, opts)
+		},
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+	addCommonFlags(cmd, ioStreams)
+	cmd.PersistentFlags().StringVarP(&opts.targetNamespace, "namespace", "n", "", "target namespace where pac is installed")
+	cmd.PersistentFlags().StringVar(&opts.GiteaAPIURL, "gitea-api-url", "", "Gitea base URL, e.g. https://gitea.example.com")
+	return cmd
+}
+
+func askGiteaBaseURL(ioStreams *cli.IOStreams) (string, error) {
+	fmt.Fprint(ioStreams.Out, "? Please enter the Gitea base URL (e.g https://gitea.example.com): ")
+	reader := bufio.NewReader(ioStreams.In)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// createGiteaSecret walks the user through creating the OAuth2 Application
+// by hand on the Gitea settings page (Gitea has no App-manifest-style
+// redirect flow like GitHub's, so the Client ID/Secret cannot be captured
+// automatically) and writes the pasted-back credentials to the PAC secret.
+func createGiteaSecret(ctx context.Context, run *params.Run, opts *bootstrapOpts) error {
+	baseURL := strings.TrimSuffix(opts.GiteaAPIURL, "/")
+
+	creds, err := askGiteaOAuthCredentials(ctx, opts, baseURL)
+	if err != nil {
+		return err
+	}
+
+	return writeGiteaSecret(ctx, run, opts, baseURL, creds)
+}
+
+// askGiteaOAuthCredentials prints the manual steps to create a Gitea
+// OAuth2 application and prompts the user to paste back the generated
+// Client ID and Client Secret. The prompt runs on its own goroutine so a
+// cancelled ctx can interrupt it instead of blocking forever on stdin.
+func askGiteaOAuthCredentials(ctx context.Context, opts *bootstrapOpts, baseURL string) (*giteaOAuthCredentials, error) {
+	fmt.Fprintf(opts.ioStreams.Out, "🌍 Please open %s/user/settings/applications in your browser and create an OAuth2 Application named \"pipelines-as-code\".\n", baseURL)
+	fmt.Fprintln(opts.ioStreams.Out, "   Once created, Gitea will show you the generated Client ID and Client Secret, paste them below.")
+
+	creds := make(chan *giteaOAuthCredentials, 1)
+	errCh := make(chan error, 1)
+
+	go func() {
+		reader := bufio.NewReader(opts.ioStreams.In)
+
+		fmt.Fprint(opts.ioStreams.Out, "? Client ID: ")
+		clientID, err := reader.ReadString('\n')
+		if err != nil {
+			errCh <- err
+			return
+		}
+
+		fmt.Fprint(opts.ioStreams.Out, "? Client Secret: ")
+		clientSecret, err := reader.ReadString('\n')
+		if err != nil {
+			errCh <- err
+			return
+		}
+
+		creds <- &giteaOAuthCredentials{
+			ClientID:     strings.TrimSpace(clientID),
+			ClientSecret: strings.TrimSpace(clientSecret),
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return nil, err
+	case c := <-creds:
+		return c, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// writeGiteaSecret stores the Gitea OAuth2 credentials under the keys the
+// Gitea provider already reads from pipelines-as-code-secret.
+func writeGiteaSecret(ctx context.Context, run *params.Run, opts *bootstrapOpts, baseURL string, creds *giteaOAuthCredentials) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: opts.targetNamespace,
+		},
+		StringData: map[string]string{
+			"gitea.application-id":     creds.ClientID,
+			"gitea.application-secret": creds.ClientSecret,
+			"gitea.api-url":            baseURL,
+		},
+	}
+
+	secrets := run.Clients.Kube.CoreV1().Secrets(opts.targetNamespace)
+	if _, err := secrets.Get(ctx, secretName, metav1.GetOptions{}); err == nil {
+		_, err := secrets.Update(ctx, secret, metav1.UpdateOptions{})
+		return err
+	}
+	_, err := secrets.Create(ctx, secret, metav1.CreateOptions{})
+	return err
+}
@@ -0,0 +1,38 @@
+package info
+
+// Event holds the normalized view of a provider webhook payload that the
+// rest of Pipelines as Code (matching, ACL, status reporting) operates on.
+type Event struct {
+	// Organization is the owner (user or org) of the repository.
+	Organization string
+	// Repository is the repository name.
+	Repository string
+	// DefaultBranch is the branch configured as default on the repository.
+	DefaultBranch string
+	// BaseBranch is the branch the event is targeting (PR base or push ref).
+	BaseBranch string
+	// HeadBranch is the branch the event originates from (PR head).
+	HeadBranch string
+	// SHA is the commit sha the event is attached to.
+	SHA string
+	// URL is the clone/HTML URL of the repository.
+	URL string
+
+	// Sender is the login of the user who triggered the event.
+	Sender string
+	// SenderTeams lists the names of the teams Sender belongs to within
+	// Organization, when the provider includes that in the webhook
+	// payload. Used by CODEOWNERS @org/team matching to avoid an extra
+	// API round-trip; falls back to a live lookup when empty.
+	SenderTeams []string
+
+	// EventType is the provider event type, e.g. "pull_request", "issue_comment".
+	EventType string
+	// TriggerTarget qualifies why we run, e.g. "pull-request", "ok-to-test-comment".
+	TriggerTarget string
+	// Event is the raw, provider-specific payload.
+	Event any
+
+	// PullRequestNumber is the index of the pull/merge request if any.
+	PullRequestNumber int
+}
@@ -0,0 +1,21 @@
+// Package settings holds Pipelines as Code's global controller
+// configuration, the values that are shared by every provider and event
+// rather than being carried on a single info.Event.
+package settings
+
+import "time"
+
+// Settings is typically loaded from the pipelines-as-code ConfigMap and
+// threaded down to the providers that need it. A nil field means "use the
+// provider's own default"; a pointer to zero is an explicit request for
+// that zero value (e.g. a 0 TTL to disable time-based freshness entirely),
+// except for GiteaCacheSize.
+type Settings struct {
+	// GiteaCacheSize is the maximum number of Gitea API GET responses kept
+	// in the in-memory response cache. A size of zero or less falls back to
+	// the provider's default size.
+	GiteaCacheSize *int
+	// GiteaCacheTTL is how long a cached Gitea API response is reused
+	// without even issuing a conditional request.
+	GiteaCacheTTL *time.Duration
+}
@@ -0,0 +1,171 @@
+package gitea
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	giteaStruct "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/sdk/gitea"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"gopkg.in/yaml.v3"
+)
+
+const ownerFilePath = "OWNERS"
+
+// ownerFile is the expected shape of an OWNERS file at the root of a
+// repository.
+type ownerFile struct {
+	Approvers []string `yaml:"approvers"`
+}
+
+// IsAllowed decides whether the event's sender is allowed to trigger
+// PipelineRuns on this repository, either directly or via a /ok-to-test
+// comment left by an allowed user.
+func (v *Provider) IsAllowed(ctx context.Context, event *info.Event) (bool, error) {
+	if event.TriggerTarget == "ok-to-test-comment" {
+		return v.aclCheckFromOkToTestComment(ctx, event)
+	}
+	return v.aclCheckAll(ctx, event)
+}
+
+// aclCheckFromOkToTestComment looks at the issue comments of the event's
+// issue/pull request and checks whether one of them is a /ok-to-test
+// comment left by someone that is allowed.
+func (v *Provider) aclCheckFromOkToTestComment(ctx context.Context, event *info.Event) (bool, error) {
+	issueCommentPayload, ok := event.Event.(*giteaStruct.IssueCommentPayload)
+	if !ok {
+		return false, nil
+	}
+
+	issueNumber, err := issueNumberFromURL(issueCommentPayload.Issue.URL)
+	if err != nil {
+		return false, err
+	}
+
+	comments, _, err := v.Client.ListIssueComments(event.Organization, event.Repository, issueNumber, gitea.ListIssueCommentOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	for _, comment := range comments {
+		if !strings.Contains(comment.Body, "/ok-to-test") {
+			continue
+		}
+		commenterEvent := &info.Event{
+			Organization:      event.Organization,
+			Repository:        event.Repository,
+			Sender:            comment.Poster.UserName,
+			DefaultBranch:     event.DefaultBranch,
+			BaseBranch:        event.BaseBranch,
+			HeadBranch:        event.HeadBranch,
+			PullRequestNumber: event.PullRequestNumber,
+		}
+		allowed, err := v.aclCheckAll(ctx, commenterEvent)
+		if err != nil {
+			return false, err
+		}
+		if allowed {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// aclCheckAll runs the full ACL resolution for event.Sender: it is allowed
+// when it owns the repository's namespace, is a collaborator, or is approved
+// by the repository's CODEOWNERS file, or by its OWNERS file when there is
+// no CODEOWNERS file (see checkOwnerFile).
+func (v *Provider) aclCheckAll(ctx context.Context, event *info.Event) (bool, error) {
+	if event.Sender == event.Organization {
+		return true, nil
+	}
+
+	isCollaborator, err := v.checkCollaborator(event)
+	if err != nil {
+		return false, err
+	}
+	if isCollaborator {
+		return true, nil
+	}
+
+	return v.checkOwnerFile(ctx, event)
+}
+
+func (v *Provider) checkCollaborator(event *info.Event) (bool, error) {
+	isCollaborator, resp, err := v.Client.IsCollaborator(event.Organization, event.Repository, event.Sender)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return isCollaborator, nil
+}
+
+// checkOwnerFile resolves ACL via the repository's CODEOWNERS file when
+// present, falling back to the flat OWNERS file otherwise.
+func (v *Provider) checkOwnerFile(ctx context.Context, event *info.Event) (bool, error) {
+	allowed, hasCodeowners, err := v.checkCodeowners(ctx, event)
+	if err != nil {
+		return false, err
+	}
+	if hasCodeowners {
+		return allowed, nil
+	}
+
+	approvers, err := v.getOwnerApprovers(ctx, event, ownerFilePath)
+	if err != nil {
+		return false, err
+	}
+	return ownerApproves(approvers, event.Sender), nil
+}
+
+func (v *Provider) getOwnerApprovers(_ context.Context, event *info.Event, path string) ([]string, error) {
+	content, resp, err := v.Client.GetContents(event.Organization, event.Repository, event.DefaultBranch, path)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if content == nil || content.Content == nil {
+		return nil, nil
+	}
+
+	decoded, err := decodeBase64(*content.Content)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode %s: %w", path, err)
+	}
+
+	var owners ownerFile
+	if err := yaml.Unmarshal(decoded, &owners); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+	return owners.Approvers, nil
+}
+
+func ownerApproves(approvers []string, sender string) bool {
+	for _, approver := range approvers {
+		if strings.EqualFold(strings.TrimPrefix(approver, "@"), sender) {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeBase64(s string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(s)
+}
+
+func issueNumberFromURL(url string) (int64, error) {
+	parts := strings.Split(url, "/")
+	number, err := strconv.ParseInt(parts[len(parts)-1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse issue number from %s: %w", url, err)
+	}
+	return number, nil
+}
@@ -0,0 +1,137 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+// actionStatusPollInterval is how often we poll Gitea Actions for the
+// status of a dispatched workflow run. Var rather than const so tests can
+// shrink it instead of waiting out the real interval.
+var actionStatusPollInterval = 5 * time.Second
+
+// DispatchAction triggers workflowRef (a file under .gitea/workflows, e.g.
+// "pull-request.yml") on event's branch via the Gitea Actions
+// workflow-dispatch API, passing inputs through, then blocks polling the
+// run until every job reaches a terminal status, mirroring each job into
+// the same commit-status/comment surface CreateStatus already uses.
+func (v *Provider) DispatchAction(ctx context.Context, event *info.Event, workflowRef string, inputs map[string]string) error {
+	opt := gitea.CreateActionWorkflowDispatch{
+		Ref:    event.HeadBranch,
+		Inputs: inputs,
+	}
+	if _, err := v.Client.CreateWorkflowDispatch(event.Organization, event.Repository, workflowRef, opt); err != nil {
+		return fmt.Errorf("could not dispatch gitea actions workflow %s: %w", workflowRef, err)
+	}
+
+	runs, err := v.waitForActionRuns(ctx, event, workflowRef)
+	if err != nil {
+		return err
+	}
+	return v.reportActionRuns(ctx, event, runs)
+}
+
+// waitForActionRuns polls the actions API until every job of workflowRef on
+// event.SHA (i.e. every gitea.ActionTask sharing that head SHA and workflow
+// ID) has reached a terminal status (see actionRunIsDone). Gitea registers each job's
+// ActionTask asynchronously as the runner picks it up, so a single poll
+// seeing N done jobs doesn't mean there isn't a slower Nth+1 job whose row
+// hasn't appeared yet; we only trust the result once two consecutive polls
+// agree on the same non-zero job count.
+func (v *Provider) waitForActionRuns(ctx context.Context, event *info.Event, workflowRef string) ([]*gitea.ActionTask, error) {
+	ticker := time.NewTicker(actionStatusPollInterval)
+	defer ticker.Stop()
+
+	prevCount := -1
+	for {
+		runs, err := v.actionRunsForWorkflow(event, workflowRef)
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) > 0 && len(runs) == prevCount && allActionRunsDone(runs) {
+			return runs, nil
+		}
+		prevCount = len(runs)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// actionRunsForWorkflow returns every job of the dispatched workflow, i.e.
+// all the gitea.ActionTask entries matching event.SHA and workflowRef.
+func (v *Provider) actionRunsForWorkflow(event *info.Event, workflowRef string) ([]*gitea.ActionTask, error) {
+	tasks, _, err := v.Client.ListRepoActionTasks(event.Organization, event.Repository, gitea.ListActionTasksOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var runs []*gitea.ActionTask
+	for _, task := range tasks {
+		if task.HeadSHA == event.SHA && task.WorkflowID == workflowRef {
+			runs = append(runs, task)
+		}
+	}
+	return runs, nil
+}
+
+func allActionRunsDone(runs []*gitea.ActionTask) bool {
+	for _, run := range runs {
+		if !actionRunIsDone(run.Status) {
+			return false
+		}
+	}
+	return true
+}
+
+func actionRunIsDone(status string) bool {
+	switch status {
+	case "success", "failure", "cancelled", "skipped":
+		return true
+	default:
+		return false
+	}
+}
+
+// reportActionRuns translates each finished job of a Gitea Actions run into
+// its own commit status, the same surface CreateStatus uses for Tekton
+// PipelineRun task results, so the PR checks panel lists one entry per job
+// regardless of which backend actually ran.
+func (v *Provider) reportActionRuns(ctx context.Context, event *info.Event, runs []*gitea.ActionTask) error {
+	for _, run := range runs {
+		if err := v.reportActionRun(ctx, event, run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *Provider) reportActionRun(_ context.Context, event *info.Event, run *gitea.ActionTask) error {
+	statusOpt := gitea.CreateStatusOption{
+		State:       actionStatusToCommitStatus(run.Status),
+		TargetURL:   run.HTMLURL,
+		Description: fmt.Sprintf("Gitea Actions job %s: %s", run.Name, run.Status),
+		Context:     fmt.Sprintf("pipelines-as-code/gitea-actions/%s", run.Name),
+	}
+	_, _, err := v.Client.CreateStatus(event.Organization, event.Repository, event.SHA, statusOpt)
+	return err
+}
+
+func actionStatusToCommitStatus(status string) gitea.StatusState {
+	switch status {
+	case "success":
+		return gitea.StatusSuccess
+	case "failure", "cancelled":
+		return gitea.StatusFailure
+	case "skipped":
+		return gitea.StatusWarning
+	default:
+		return gitea.StatusPending
+	}
+}
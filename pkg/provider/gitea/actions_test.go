@@ -0,0 +1,137 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tgitea "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitea/test"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestActionRunIsDone(t *testing.T) {
+	tests := map[string]bool{
+		"success":   true,
+		"failure":   true,
+		"cancelled": true,
+		"skipped":   true,
+		"running":   false,
+		"waiting":   false,
+	}
+	for status, want := range tests {
+		assert.Equal(t, actionRunIsDone(status), want, status)
+	}
+}
+
+func TestAllActionRunsDone(t *testing.T) {
+	assert.Assert(t, allActionRunsDone([]*gitea.ActionTask{{Status: "success"}, {Status: "failure"}}))
+	assert.Assert(t, !allActionRunsDone([]*gitea.ActionTask{{Status: "success"}, {Status: "running"}}))
+}
+
+func TestActionStatusToCommitStatus(t *testing.T) {
+	assert.Equal(t, actionStatusToCommitStatus("success"), gitea.StatusSuccess)
+	assert.Equal(t, actionStatusToCommitStatus("failure"), gitea.StatusFailure)
+	assert.Equal(t, actionStatusToCommitStatus("skipped"), gitea.StatusWarning)
+	assert.Equal(t, actionStatusToCommitStatus("running"), gitea.StatusPending)
+}
+
+func TestDispatchAction(t *testing.T) {
+	event := &info.Event{
+		Organization: "owner",
+		Repository:   "repo",
+		HeadBranch:   "main",
+		SHA:          "abcdef",
+	}
+
+	origInterval := actionStatusPollInterval
+	actionStatusPollInterval = 10 * time.Millisecond
+	defer func() { actionStatusPollInterval = origInterval }()
+
+	fakeclient, mux, teardown := tgitea.Setup(t)
+	defer teardown()
+	ctx, _ := rtesting.SetupFakeContext(t)
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/actions/workflows/pr.yml/dispatches", event.Organization, event.Repository),
+		func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusNoContent)
+		})
+	mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/actions/tasks", event.Organization, event.Repository),
+		func(rw http.ResponseWriter, r *http.Request) {
+			runs := []*gitea.ActionTask{{Name: "build", HeadSHA: event.SHA, WorkflowID: "pr.yml", Status: "success", HTMLURL: "http://url.com/run/1"}}
+			b, err := json.Marshal(runs)
+			assert.NilError(t, err)
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write(b)
+		})
+	var statusContexts []string
+	mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/statuses/%s", event.Organization, event.Repository, event.SHA),
+		func(rw http.ResponseWriter, r *http.Request) {
+			var opt gitea.CreateStatusOption
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&opt))
+			statusContexts = append(statusContexts, opt.Context)
+			rw.WriteHeader(http.StatusCreated)
+			_, _ = rw.Write([]byte("{}"))
+		})
+
+	gprovider := Provider{Client: fakeclient}
+	err := gprovider.DispatchAction(ctx, event, "pr.yml", map[string]string{"foo": "bar"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, statusContexts, []string{"pipelines-as-code/gitea-actions/build"})
+}
+
+func TestDispatchActionReportsEveryJob(t *testing.T) {
+	event := &info.Event{
+		Organization: "owner",
+		Repository:   "repo",
+		HeadBranch:   "main",
+		SHA:          "abcdef",
+	}
+
+	origInterval := actionStatusPollInterval
+	actionStatusPollInterval = 10 * time.Millisecond
+	defer func() { actionStatusPollInterval = origInterval }()
+
+	fakeclient, mux, teardown := tgitea.Setup(t)
+	defer teardown()
+	ctx, _ := rtesting.SetupFakeContext(t)
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/actions/workflows/pr.yml/dispatches", event.Organization, event.Repository),
+		func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusNoContent)
+		})
+	mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/actions/tasks", event.Organization, event.Repository),
+		func(rw http.ResponseWriter, r *http.Request) {
+			runs := []*gitea.ActionTask{
+				{Name: "build", HeadSHA: event.SHA, WorkflowID: "pr.yml", Status: "success", HTMLURL: "http://url.com/run/1"},
+				{Name: "test", HeadSHA: event.SHA, WorkflowID: "pr.yml", Status: "failure", HTMLURL: "http://url.com/run/2"},
+			}
+			b, err := json.Marshal(runs)
+			assert.NilError(t, err)
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write(b)
+		})
+	var statusContexts []string
+	mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/statuses/%s", event.Organization, event.Repository, event.SHA),
+		func(rw http.ResponseWriter, r *http.Request) {
+			var opt gitea.CreateStatusOption
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&opt))
+			statusContexts = append(statusContexts, opt.Context)
+			rw.WriteHeader(http.StatusCreated)
+			_, _ = rw.Write([]byte("{}"))
+		})
+
+	gprovider := Provider{Client: fakeclient}
+	err := gprovider.DispatchAction(ctx, event, "pr.yml", map[string]string{"foo": "bar"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, statusContexts, []string{"pipelines-as-code/gitea-actions/build", "pipelines-as-code/gitea-actions/test"})
+}
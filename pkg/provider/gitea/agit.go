@@ -0,0 +1,100 @@
+package gitea
+
+import (
+	"strings"
+
+	giteaStruct "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/sdk/gitea"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+// agitRefPrefix is the ref Gitea/Forgejo receive a push on to trigger an
+// AGit-flow pull request, e.g. "refs/for/main/my-topic".
+const agitRefPrefix = "refs/for/"
+
+// isAGitPush reports whether ref is an AGit-flow push ref.
+func isAGitPush(ref string) bool {
+	return strings.HasPrefix(ref, agitRefPrefix)
+}
+
+// parseAGitRef splits an AGit push ref into its target branch and optional
+// topic branch, e.g. "refs/for/main/my-topic" -> ("main", "my-topic") and
+// "refs/for/main" -> ("main", "").
+func parseAGitRef(ref string) (targetBranch, topic string) {
+	rest := strings.TrimPrefix(ref, agitRefPrefix)
+	targetBranch, topic, _ = strings.Cut(rest, "/")
+	return targetBranch, topic
+}
+
+// agitEventFromPush synthesizes a pull-request info.Event out of an AGit
+// push payload, so the rest of the matching pipeline (IsAllowed,
+// /ok-to-test, PipelineRun selection) runs exactly as it would for a
+// regular pull request.
+func agitEventFromPush(payload *giteaStruct.PushPayload) *info.Event {
+	targetBranch, topic := parseAGitRef(payload.Ref)
+
+	event := &info.Event{
+		Organization:  payload.Repo.Owner.UserName,
+		Repository:    payload.Repo.Name,
+		DefaultBranch: payload.Repo.DefaultBranch,
+		BaseBranch:    targetBranch,
+		HeadBranch:    topic,
+		SHA:           payload.After,
+		URL:           payload.Repo.HTMLURL,
+		Sender:        payload.Pusher.UserName,
+		EventType:     "pull_request",
+		TriggerTarget: "pull-request",
+	}
+	if event.HeadBranch == "" {
+		event.HeadBranch = event.Sender
+	}
+	return event
+}
+
+// ParsePushEvent builds the info.Event for a raw push payload, detecting
+// AGit-flow pushes (refs/for/<branch>[/<topic>]) and synthesizing the
+// equivalent pull-request event for them so matching and ACL gating run
+// unchanged. Regular branch pushes are returned as a plain push event.
+func (v *Provider) ParsePushEvent(payload *giteaStruct.PushPayload) (*info.Event, error) {
+	if !isAGitPush(payload.Ref) {
+		return &info.Event{
+			Organization:  payload.Repo.Owner.UserName,
+			Repository:    payload.Repo.Name,
+			DefaultBranch: payload.Repo.DefaultBranch,
+			BaseBranch:    payload.Ref,
+			SHA:           payload.After,
+			URL:           payload.Repo.HTMLURL,
+			Sender:        payload.Pusher.UserName,
+			EventType:     "push",
+			TriggerTarget: "push",
+		}, nil
+	}
+
+	event := agitEventFromPush(payload)
+	pr, err := v.findAGitPullRequest(event)
+	if err != nil {
+		return nil, err
+	}
+	if pr != nil {
+		event.PullRequestNumber = int(pr.Index)
+		event.HeadBranch = pr.Head.Ref
+	}
+	return event, nil
+}
+
+// findAGitPullRequest looks up the pull request that Gitea/Forgejo creates
+// server-side for an AGit push, matched by its head branch.
+func (v *Provider) findAGitPullRequest(event *info.Event) (*gitea.PullRequest, error) {
+	prs, _, err := v.Client.ListRepoPullRequests(event.Organization, event.Repository, gitea.ListPullRequestsOptions{
+		State: gitea.StateOpen,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, pr := range prs {
+		if pr.Head != nil && pr.Head.Ref == event.HeadBranch && pr.Base != nil && pr.Base.Ref == event.BaseBranch {
+			return pr, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,79 @@
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	giteaStruct "code.gitea.io/gitea/modules/structs"
+	tgitea "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitea/test"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestParseAGitRef(t *testing.T) {
+	tests := []struct {
+		ref        string
+		wantBranch string
+		wantTopic  string
+		wantIsAGit bool
+	}{
+		{ref: "refs/for/main/topic", wantBranch: "main", wantTopic: "topic", wantIsAGit: true},
+		{ref: "refs/for/main", wantBranch: "main", wantTopic: "", wantIsAGit: true},
+		{ref: "refs/heads/main", wantIsAGit: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			assert.Equal(t, isAGitPush(tt.ref), tt.wantIsAGit)
+			if !tt.wantIsAGit {
+				return
+			}
+			branch, topic := parseAGitRef(tt.ref)
+			assert.Equal(t, branch, tt.wantBranch)
+			assert.Equal(t, topic, tt.wantTopic)
+		})
+	}
+}
+
+func TestParsePushEventAGitFlowToAllowedPipelineRun(t *testing.T) {
+	payload := &giteaStruct.PushPayload{
+		Ref:   "refs/for/main/my-topic",
+		After: "deadbeef",
+		Repo: &giteaStruct.Repository{
+			Name:          "repo",
+			DefaultBranch: "main",
+			HTMLURL:       "http://url.com/owner/repo",
+			Owner:         &giteaStruct.User{UserName: "owner"},
+		},
+		Pusher: &giteaStruct.User{UserName: "owners-approver"},
+	}
+
+	fakeclient, mux, teardown := tgitea.Setup(t)
+	defer teardown()
+	ctx, _ := rtesting.SetupFakeContext(t)
+
+	mux.HandleFunc("/repos/owner/repo/pulls", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `[{"number": 1, "head": {"ref": "my-topic"}, "base": {"ref": "main"}}]`)
+	})
+	// Sender is not a collaborator: IsAllowed must fall through to the
+	// OWNERS file instead of trivially passing via the org short-circuit.
+	mux.HandleFunc("/repos/owner/repo/collaborators/owners-approver", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/repos/owner/repo/contents/OWNERS", func(rw http.ResponseWriter, r *http.Request) {
+		writeContents(t, rw, "approvers:\n  - owners-approver\n")
+	})
+
+	gprovider := Provider{Client: fakeclient}
+	event, err := gprovider.ParsePushEvent(payload)
+	assert.NilError(t, err)
+	assert.Equal(t, event.BaseBranch, "main")
+	assert.Equal(t, event.HeadBranch, "my-topic")
+	assert.Equal(t, event.PullRequestNumber, 1)
+	assert.Equal(t, event.TriggerTarget, "pull-request")
+	assert.Assert(t, event.Sender != event.Organization)
+
+	allowed, err := gprovider.IsAllowed(ctx, event)
+	assert.NilError(t, err)
+	assert.Assert(t, allowed)
+}
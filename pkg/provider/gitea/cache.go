@@ -0,0 +1,184 @@
+package gitea
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+)
+
+// CacheConfig controls the size and freshness of the Gitea API response
+// cache used by IsAllowed.
+type CacheConfig struct {
+	// Size is the maximum number of cached responses kept in memory.
+	Size int
+	// TTL is how long a cached entry is reused without even issuing a
+	// conditional request.
+	TTL time.Duration
+}
+
+// DefaultCacheConfig is used when the controller's global settings do not
+// override the Gitea response cache settings.
+var DefaultCacheConfig = CacheConfig{
+	Size: 256,
+	TTL:  5 * time.Minute,
+}
+
+// cacheConfigFromSettings builds a CacheConfig from the controller's global
+// configuration, falling back to DefaultCacheConfig for any field left nil.
+// A non-nil pointer is honored verbatim, including an explicit zero TTL.
+func cacheConfigFromSettings(s settings.Settings) CacheConfig {
+	cfg := DefaultCacheConfig
+	if s.GiteaCacheSize != nil {
+		cfg.Size = *s.GiteaCacheSize
+	}
+	if s.GiteaCacheTTL != nil {
+		cfg.TTL = *s.GiteaCacheTTL
+	}
+	return cfg
+}
+
+type cacheEntry struct {
+	key          string
+	body         []byte
+	statusCode   int
+	header       http.Header
+	etag         string
+	lastModified string
+	storedAt     time.Time
+}
+
+// responseCache is a small in-memory LRU cache of HTTP responses, keyed by
+// request (repo, endpoint) pairs, with revalidation via ETag/Last-Modified.
+type responseCache struct {
+	mu      sync.Mutex
+	maxSize int
+	ttl     time.Duration
+	ll      *list.List
+	items   map[string]*list.Element
+}
+
+// newResponseCache builds the cache from cfg as given: callers that want
+// DefaultCacheConfig's fallbacks applied should go through
+// cacheConfigFromSettings first. A zero-value Size falls back to
+// DefaultCacheConfig.Size since a cache with no capacity makes no sense;
+// TTL is honored as-is, including an explicit zero (every entry is
+// revalidated on each read).
+func newResponseCache(cfg CacheConfig) *responseCache {
+	if cfg.Size <= 0 {
+		cfg.Size = DefaultCacheConfig.Size
+	}
+	return &responseCache{
+		maxSize: cfg.Size,
+		ttl:     cfg.TTL,
+		ll:      list.New(),
+		items:   map[string]*list.Element{},
+	}
+}
+
+// get returns a copy of the cached entry for key, never the shared pointer
+// stored in the cache: callers are free to read and even mutate it (e.g. to
+// bump storedAt on revalidation) without racing other goroutines calling
+// get()/set() for the same key concurrently.
+func (c *responseCache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	entryCopy := *el.Value.(*cacheEntry)
+	return &entryCopy, true
+}
+
+func (c *responseCache) set(entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[entry.key]; ok {
+		el.Value = entry
+		c.ll.MoveToFront(el)
+		return
+	}
+	el := c.ll.PushFront(entry)
+	c.items[entry.key] = el
+	for c.ll.Len() > c.maxSize {
+		oldest := c.ll.Back()
+		if oldest == nil {
+			break
+		}
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// cachingTransport wraps a http.RoundTripper so that GET requests are
+// revalidated with If-None-Match/If-Modified-Since, and a 304 response is
+// served out of the cache instead of being re-fetched/re-parsed.
+type cachingTransport struct {
+	next  http.RoundTripper
+	cache *responseCache
+}
+
+func newCachingTransport(next http.RoundTripper, cfg CacheConfig) *cachingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &cachingTransport{next: next, cache: newResponseCache(cfg)}
+}
+
+func (c *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return c.next.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	entry, cached := c.cache.get(key)
+	if cached && time.Since(entry.storedAt) < c.cache.ttl {
+		return entry.toResponse(req), nil
+	}
+
+	if cached {
+		// RoundTrip must not mutate the caller-owned request, so clone it
+		// before setting the revalidation headers.
+		req = req.Clone(req.Context())
+		if entry.etag != "" {
+			req.Header.Set("If-None-Match", entry.etag)
+		}
+		if entry.lastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.lastModified)
+		}
+	}
+
+	resp, err := c.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		entry.storedAt = time.Now()
+		c.cache.set(entry)
+		return entry.toResponse(req), nil
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		body, err := readAndRestoreBody(resp)
+		if err != nil {
+			return nil, err
+		}
+		c.cache.set(&cacheEntry{
+			key:          key,
+			body:         body,
+			statusCode:   resp.StatusCode,
+			header:       resp.Header.Clone(),
+			etag:         resp.Header.Get("ETag"),
+			lastModified: resp.Header.Get("Last-Modified"),
+			storedAt:     time.Now(),
+		})
+	}
+
+	return resp, nil
+}
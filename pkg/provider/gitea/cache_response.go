@@ -0,0 +1,34 @@
+package gitea
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// toResponse replays a cached entry as a fresh *http.Response for req.
+func (e *cacheEntry) toResponse(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(e.statusCode),
+		StatusCode:    e.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+// readAndRestoreBody drains resp.Body, returns its bytes, and resets
+// resp.Body so downstream decoders can still read it.
+func readAndRestoreBody(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
@@ -0,0 +1,247 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+// codeownersPaths are the locations checked for a CODEOWNERS file, in
+// order, mirroring the convention Gitea/Forgejo use for issue assignment.
+var codeownersPaths = []string{
+	".gitea/CODEOWNERS",
+	"docs/CODEOWNERS",
+	"CODEOWNERS",
+}
+
+// codeownersRule is a single "pattern owner owner..." line of a CODEOWNERS
+// file.
+type codeownersRule struct {
+	pattern string
+	owners  []string
+}
+
+// checkCodeowners resolves ACL for event.Sender using the repository's
+// CODEOWNERS file, if any. The second return value reports whether a
+// CODEOWNERS file was found at all, so callers can fall back to OWNERS
+// when it wasn't.
+func (v *Provider) checkCodeowners(ctx context.Context, event *info.Event) (allowed, found bool, err error) {
+	rules, err := v.fetchCodeowners(event)
+	if err != nil {
+		return false, false, err
+	}
+	if rules == nil {
+		return false, false, nil
+	}
+
+	changedFiles, err := v.changedFiles(ctx, event)
+	if err != nil {
+		return false, true, err
+	}
+	// No changed files to scope the approval against, e.g. an event with no
+	// pull request number: CODEOWNERS approves nobody then, and OWNERS is
+	// not consulted either.
+	if len(changedFiles) == 0 {
+		return false, true, nil
+	}
+
+	for _, file := range changedFiles {
+		owners := ownersForPath(rules, file)
+		if len(owners) == 0 {
+			return false, true, nil
+		}
+		ownsFile, err := v.ownsPath(event, owners)
+		if err != nil {
+			return false, true, err
+		}
+		if !ownsFile {
+			return false, true, nil
+		}
+	}
+	return true, true, nil
+}
+
+// fetchCodeowners returns the parsed CODEOWNERS file for the repository, or
+// nil if none of the conventional locations has one.
+func (v *Provider) fetchCodeowners(event *info.Event) ([]codeownersRule, error) {
+	for _, codeownersPath := range codeownersPaths {
+		content, resp, err := v.Client.GetContents(event.Organization, event.Repository, event.DefaultBranch, codeownersPath)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if content == nil || content.Content == nil {
+			continue
+		}
+		decoded, err := decodeContentsResponse(content)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode %s: %w", codeownersPath, err)
+		}
+		return parseCodeowners(decoded), nil
+	}
+	return nil, nil
+}
+
+// parseCodeowners parses a CODEOWNERS file body into ordered rules, the
+// same syntax used by GitHub/GitLab/Gitea: "<pattern> <owner> [owner...]",
+// blank lines and lines starting with "#" are ignored.
+func parseCodeowners(content []byte) []codeownersRule {
+	var rules []codeownersRule
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		rules = append(rules, codeownersRule{pattern: fields[0], owners: fields[1:]})
+	}
+	return rules
+}
+
+// ownersForPath returns the owners of the last CODEOWNERS rule matching
+// file, later rules taking precedence over earlier ones as per convention.
+func ownersForPath(rules []codeownersRule, file string) []string {
+	var owners []string
+	for _, rule := range rules {
+		if matchesCodeownersPattern(rule.pattern, file) {
+			owners = rule.owners
+		}
+	}
+	return owners
+}
+
+// matchesCodeownersPattern matches a gitignore-style CODEOWNERS pattern
+// against a repository-relative file path.
+func matchesCodeownersPattern(pattern, file string) bool {
+	pattern = strings.TrimPrefix(pattern, "/")
+	if strings.HasSuffix(pattern, "/") {
+		return strings.HasPrefix(file, pattern) || file == strings.TrimSuffix(pattern, "/")
+	}
+	if strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, file)
+		return ok
+	}
+	// A pattern with no slash matches the basename anywhere in the tree.
+	ok, _ := path.Match(pattern, path.Base(file))
+	return ok
+}
+
+// ownsPath reports whether event.Sender is one of owners, either directly
+// (@user) or through team membership (@org/team).
+func (v *Provider) ownsPath(event *info.Event, owners []string) (bool, error) {
+	for _, owner := range owners {
+		owner = strings.TrimPrefix(owner, "@")
+		if strings.Contains(owner, "/") {
+			org, team, _ := strings.Cut(owner, "/")
+			if org == event.Organization && isTeamInSenderTeams(event.SenderTeams, team) {
+				return true, nil
+			}
+			isMember, err := v.isTeamMember(org, team, event.Sender)
+			if err != nil {
+				return false, err
+			}
+			if isMember {
+				return true, nil
+			}
+			continue
+		}
+		if strings.EqualFold(owner, event.Sender) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isTeamInSenderTeams reports whether team is already known to be one of
+// the sender's teams from the webhook payload, saving the
+// SearchOrgTeams/GetTeamMember round-trip isTeamMember otherwise needs.
+func isTeamInSenderTeams(senderTeams []string, team string) bool {
+	for _, t := range senderTeams {
+		if strings.EqualFold(t, team) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *Provider) isTeamMember(org, team, user string) (bool, error) {
+	teams, _, err := v.Client.SearchOrgTeams(org, &gitea.SearchTeamsOptions{Query: team})
+	if err != nil {
+		return false, err
+	}
+	for _, t := range teams {
+		if !strings.EqualFold(t.Name, team) {
+			continue
+		}
+		isMember, resp, err := v.Client.GetTeamMember(t.ID, user)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return isMember != nil, nil
+	}
+	return false, nil
+}
+
+// changedFilesPageSize is the requested page size used to page through
+// every file of a pull request: large PRs can have more changed files than
+// fit on the API's default single page. The server is free to cap this
+// lower, so pages are consumed until an empty one comes back rather than
+// until a short one does.
+const changedFilesPageSize = 100
+
+// changedFilesMaxPages bounds how many pages changedFiles will follow, so a
+// server that never returns an empty page (e.g. one that ignores the Page
+// option) can't make this loop forever. Set far above any realistic PR size
+// so it only ever trips on a non-conforming server, never on a legitimately
+// large pull request.
+const changedFilesMaxPages = 1000
+
+// changedFiles returns the list of paths touched by event's pull request,
+// paging through every result so a PR with more files than one page doesn't
+// get CODEOWNERS-approved on only the files that happened to come back on
+// the first page.
+func (v *Provider) changedFiles(ctx context.Context, event *info.Event) ([]string, error) {
+	if event.PullRequestNumber == 0 {
+		return nil, nil
+	}
+
+	var files []string
+	for page := 1; page <= changedFilesMaxPages; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		giteaFiles, _, err := v.Client.ListPullRequestFiles(event.Organization, event.Repository, int64(event.PullRequestNumber), gitea.ListPullRequestFilesOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: changedFilesPageSize},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(giteaFiles) == 0 {
+			return files, nil
+		}
+		for _, f := range giteaFiles {
+			files = append(files, f.Filename)
+		}
+	}
+	return nil, fmt.Errorf("pull request %d on %s/%s has more than %d pages of changed files",
+		event.PullRequestNumber, event.Organization, event.Repository, changedFilesMaxPages)
+}
+
+// decodeContentsResponse base64-decodes the Content field of a
+// gitea.ContentsResponse.
+func decodeContentsResponse(content *gitea.ContentsResponse) ([]byte, error) {
+	return decodeBase64(*content.Content)
+}
@@ -0,0 +1,227 @@
+package gitea
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tgitea "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitea/test"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func writeContents(t *testing.T, rw http.ResponseWriter, body string) {
+	t.Helper()
+	encoded := base64.StdEncoding.EncodeToString([]byte(body))
+	b, err := json.Marshal(gitea.ContentsResponse{Content: &encoded})
+	assert.NilError(t, err)
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(b)
+}
+
+func TestAclCheckAllCodeowners(t *testing.T) {
+	tests := []struct {
+		name           string
+		runevent       info.Event
+		codeowners     string
+		owners         string
+		teamSearchName string
+		teamMembers    []string
+		changedFiles   []string
+		allowed        bool
+	}{
+		{
+			name: "path-scoped approver owns the only changed file",
+			runevent: info.Event{
+				Organization:      "collabo",
+				Repository:        "repo",
+				Sender:            "docs-writer",
+				DefaultBranch:     "main",
+				PullRequestNumber: 1,
+			},
+			codeowners:   "docs/* @docs-writer\n",
+			changedFiles: []string{"docs/readme.md"},
+			allowed:      true,
+		},
+		{
+			name: "path-scoped approver does not own every changed file",
+			runevent: info.Event{
+				Organization:      "collabo",
+				Repository:        "repo",
+				Sender:            "docs-writer",
+				DefaultBranch:     "main",
+				PullRequestNumber: 1,
+			},
+			codeowners:   "docs/* @docs-writer\n",
+			changedFiles: []string{"docs/readme.md", "pkg/main.go"},
+			allowed:      false,
+		},
+		{
+			name: "team approver owns the changed file",
+			runevent: info.Event{
+				Organization:      "collabo",
+				Repository:        "repo",
+				Sender:            "team-member",
+				DefaultBranch:     "main",
+				PullRequestNumber: 1,
+			},
+			codeowners:     "pkg/* @collabo/core\n",
+			changedFiles:   []string{"pkg/main.go"},
+			teamSearchName: "core",
+			teamMembers:    []string{"team-member"},
+			allowed:        true,
+		},
+		{
+			name: "falls back to OWNERS when CODEOWNERS is absent",
+			runevent: info.Event{
+				Organization:      "collabo",
+				Repository:        "repo",
+				Sender:            "owners-approver",
+				DefaultBranch:     "main",
+				PullRequestNumber: 1,
+			},
+			owners:       "approvers:\n  - owners-approver\n",
+			changedFiles: []string{"pkg/main.go"},
+			allowed:      true,
+		},
+		{
+			name: "CODEOWNERS present but silent on the changed path: OWNERS is not consulted",
+			runevent: info.Event{
+				Organization:      "collabo",
+				Repository:        "repo",
+				Sender:            "owners-approver",
+				DefaultBranch:     "main",
+				PullRequestNumber: 1,
+			},
+			codeowners:   "docs/* @docs-writer\n",
+			owners:       "approvers:\n  - owners-approver\n",
+			changedFiles: []string{"pkg/main.go"},
+			allowed:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeclient, mux, teardown := tgitea.Setup(t)
+			defer teardown()
+			ctx, _ := rtesting.SetupFakeContext(t)
+
+			for _, codeownersPath := range codeownersPaths {
+				p := codeownersPath
+				path := fmt.Sprintf("/repos/%s/%s/contents/%s", tt.runevent.Organization, tt.runevent.Repository, p)
+				mux.HandleFunc(path, func(rw http.ResponseWriter, r *http.Request) {
+					if p == ".gitea/CODEOWNERS" && tt.codeowners != "" {
+						writeContents(t, rw, tt.codeowners)
+						return
+					}
+					rw.WriteHeader(http.StatusNotFound)
+				})
+			}
+
+			ownersURL := fmt.Sprintf("/repos/%s/%s/contents/OWNERS", tt.runevent.Organization, tt.runevent.Repository)
+			mux.HandleFunc(ownersURL, func(rw http.ResponseWriter, r *http.Request) {
+				if tt.owners == "" {
+					rw.WriteHeader(http.StatusNotFound)
+					return
+				}
+				writeContents(t, rw, tt.owners)
+			})
+
+			filesURL := fmt.Sprintf("/repos/%s/%s/pulls/%d/files", tt.runevent.Organization, tt.runevent.Repository, tt.runevent.PullRequestNumber)
+			mux.HandleFunc(filesURL, func(rw http.ResponseWriter, r *http.Request) {
+				files := make([]*gitea.ChangedFile, 0, len(tt.changedFiles))
+				for _, f := range tt.changedFiles {
+					files = append(files, &gitea.ChangedFile{Filename: f})
+				}
+				b, err := json.Marshal(files)
+				assert.NilError(t, err)
+				rw.WriteHeader(http.StatusOK)
+				_, _ = rw.Write(b)
+			})
+
+			if tt.teamSearchName != "" {
+				mux.HandleFunc(fmt.Sprintf("/orgs/%s/teams/search", tt.runevent.Organization), func(rw http.ResponseWriter, r *http.Request) {
+					b, err := json.Marshal(struct {
+						OK   bool          `json:"ok"`
+						Data []*gitea.Team `json:"data"`
+					}{OK: true, Data: []*gitea.Team{{ID: 1, Name: tt.teamSearchName}}})
+					assert.NilError(t, err)
+					rw.WriteHeader(http.StatusOK)
+					_, _ = rw.Write(b)
+				})
+				mux.HandleFunc("/teams/1/members/", func(rw http.ResponseWriter, r *http.Request) {
+					user := r.URL.Path[len("/teams/1/members/"):]
+					for _, m := range tt.teamMembers {
+						if m == user {
+							rw.WriteHeader(http.StatusOK)
+							fmt.Fprintf(rw, `{"login": %q}`, user)
+							return
+						}
+					}
+					rw.WriteHeader(http.StatusNotFound)
+				})
+			}
+
+			gprovider := Provider{Client: fakeclient}
+			allowed, err := gprovider.aclCheckAll(ctx, &tt.runevent)
+			assert.NilError(t, err)
+			assert.Assert(t, allowed == tt.allowed)
+		})
+	}
+}
+
+// TestChangedFilesPagesThroughAllResults pins down that changedFiles pages
+// through every result: a second page holding a file CODEOWNERS doesn't
+// cover must still be seen, or the sender would be wrongly approved on the
+// strength of only the first page.
+func TestChangedFilesPagesThroughAllResults(t *testing.T) {
+	event := info.Event{
+		Organization:      "collabo",
+		Repository:        "repo",
+		Sender:            "docs-writer",
+		DefaultBranch:     "main",
+		PullRequestNumber: 1,
+	}
+
+	fakeclient, mux, teardown := tgitea.Setup(t)
+	defer teardown()
+	ctx, _ := rtesting.SetupFakeContext(t)
+
+	for _, codeownersPath := range codeownersPaths {
+		p := codeownersPath
+		path := fmt.Sprintf("/repos/%s/%s/contents/%s", event.Organization, event.Repository, p)
+		mux.HandleFunc(path, func(rw http.ResponseWriter, r *http.Request) {
+			if p == ".gitea/CODEOWNERS" {
+				writeContents(t, rw, "docs/* @docs-writer\n")
+				return
+			}
+			rw.WriteHeader(http.StatusNotFound)
+		})
+	}
+
+	filesURL := fmt.Sprintf("/repos/%s/%s/pulls/%d/files", event.Organization, event.Repository, event.PullRequestNumber)
+	mux.HandleFunc(filesURL, func(rw http.ResponseWriter, r *http.Request) {
+		var files []*gitea.ChangedFile
+		switch r.URL.Query().Get("page") {
+		case "", "1":
+			for i := 0; i < changedFilesPageSize; i++ {
+				files = append(files, &gitea.ChangedFile{Filename: fmt.Sprintf("docs/file-%d.md", i)})
+			}
+		case "2":
+			files = []*gitea.ChangedFile{{Filename: "pkg/unowned.go"}}
+		}
+		b, err := json.Marshal(files)
+		assert.NilError(t, err)
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write(b)
+	})
+
+	gprovider := Provider{Client: fakeclient}
+	allowed, err := gprovider.aclCheckAll(ctx, &event)
+	assert.NilError(t, err)
+	assert.Assert(t, !allowed)
+}
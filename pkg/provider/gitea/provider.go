@@ -0,0 +1,41 @@
+// Package gitea implements the Pipelines as Code provider interface for
+// Gitea and Forgejo instances.
+package gitea
+
+import (
+	"net/http"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+)
+
+// Provider holds the state needed to talk to a Gitea/Forgejo instance on
+// behalf of a single event.
+type Provider struct {
+	Client *gitea.Client
+
+	Token       *string
+	APIURL      string
+	WebhookType string
+}
+
+// NewProvider builds a Provider talking to apiURL with token. GET responses
+// answered with 200 (OWNERS/CODEOWNERS contents, comments) are cached per
+// the controller's global settings and revalidated with conditional
+// requests. Other responses are not cached: collaborator checks, which
+// answer 204 or 404, are sent in full on every IsAllowed call.
+func NewProvider(apiURL, token string, globalSettings settings.Settings) (*Provider, error) {
+	cacheCfg := cacheConfigFromSettings(globalSettings)
+	httpClient := &http.Client{Transport: newCachingTransport(http.DefaultTransport, cacheCfg)}
+
+	client, err := gitea.NewClient(apiURL, gitea.SetHTTPClient(httpClient), gitea.SetToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{
+		Client: client,
+		Token:  &token,
+		APIURL: apiURL,
+	}, nil
+}
@@ -0,0 +1,31 @@
+// Package test provides helpers to unit test the Gitea provider against a
+// fake Gitea API server.
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.gitea.io/sdk/gitea"
+)
+
+// Setup spins up a httptest server backed by mux and returns a gitea.Client
+// pointed at it, along with a teardown func to close the server. The client
+// sends its requests under /api/v1, which is stripped before they reach mux,
+// so handlers are registered as e.g. "/repos/owner/repo/...". The client
+// skips the server version lookup.
+func Setup(t *testing.T) (*gitea.Client, *http.ServeMux, func()) {
+	t.Helper()
+	mux := http.NewServeMux()
+	apiHandler := http.NewServeMux()
+	apiHandler.Handle("/api/v1/", http.StripPrefix("/api/v1", mux))
+	server := httptest.NewServer(apiHandler)
+
+	client, err := gitea.NewClient(server.URL, gitea.SetGiteaVersion(""))
+	if err != nil {
+		t.Fatalf("could not create gitea client: %v", err)
+	}
+
+	return client, mux, server.Close
+}